@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+
+	"github.com/behradeslamifar/12factor/config"
+	"github.com/behradeslamifar/12factor/metrics"
+	"github.com/behradeslamifar/12factor/response"
+	"github.com/behradeslamifar/12factor/users"
+)
+
+// App wires together the dependencies (DB, router, config, logger) shared by
+// every handler and owns the HTTP server's lifecycle.
+type App struct {
+	Config config.Config
+	DB     *sqlx.DB
+	Users  users.Repository
+	Router *mux.Router
+	Logger zerolog.Logger
+
+	server *http.Server
+}
+
+// NewApp builds an App from a loaded Config, an already-connected DB pool,
+// and a user repository. It does not start listening; call Serve for that.
+func NewApp(cfg config.Config, db *sqlx.DB, userRepo users.Repository, logger zerolog.Logger) *App {
+	app := &App{
+		Config: cfg,
+		DB:     db,
+		Users:  userRepo,
+		Router: mux.NewRouter(),
+		Logger: logger,
+	}
+
+	app.RegisterRoutes()
+
+	return app
+}
+
+// RegisterRoutes attaches every HTTP route to the App's router, wrapping each
+// handler in the shared middleware chain so new routes inherit consistent
+// logging, recovery, CORS, and body-size limits for free.
+func (a *App) RegisterRoutes() {
+	chain := newMiddlewareChain(a.Logger, recoverMiddleware, corsMiddleware, maxBodyMiddleware(1<<20))
+
+	a.Router.HandleFunc("/users", chain(response.Wrap(createUserHandler(a.Users)))).Methods("POST", "OPTIONS")
+	a.Router.HandleFunc("/users", chain(response.Wrap(getUsersHandler(a.Users)))).Methods("GET", "OPTIONS")
+	a.Router.HandleFunc("/users/{id}", chain(response.Wrap(getUserHandler(a.Users)))).Methods("GET", "OPTIONS")
+	a.Router.HandleFunc("/", chain(showFormHandler)).Methods("GET")
+	a.Router.HandleFunc("/create", chain(createHandler(a.Users))).Methods("POST")
+	a.Router.HandleFunc("/health", chain(healthCheckHandler)).Methods("GET")
+	a.Router.HandleFunc("/readiness", chain(readinessCheckHandler(a.DB))).Methods("GET")
+	a.Router.HandleFunc("/startup", chain(startupCheckHandler(a.DB))).Methods("GET")
+	a.Router.HandleFunc("/metrics", chain(metrics.Handler().ServeHTTP)).Methods("GET")
+}
+
+// Serve starts the HTTP server and blocks until ctx is cancelled, at which
+// point it drains in-flight requests via Shutdown. A non-nil error other
+// than http.ErrServerClosed indicates the server failed to start or stop
+// cleanly.
+func (a *App) Serve(ctx context.Context) error {
+	a.server = &http.Server{
+		Addr:         ":" + a.Config.Port,
+		Handler:      a.Router,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		a.Logger.Info().Str("port", a.Config.Port).Msg("server listening")
+		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case <-ctx.Done():
+		return a.Shutdown(context.Background())
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Shutdown gracefully drains in-flight requests and closes the DB pool. It is
+// safe to call even if Serve was never started.
+func (a *App) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var err error
+	if a.server != nil {
+		err = a.server.Shutdown(ctx)
+	}
+
+	if a.DB != nil {
+		if closeErr := a.DB.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,162 @@
+// Package config loads application configuration with layered precedence:
+// built-in defaults, a config file, environment variables, and finally CLI
+// flags, each overriding the last.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// envPrefix is prepended to every environment variable viper looks up, e.g.
+// DB_HOST becomes APP_DB_HOST.
+const envPrefix = "APP"
+
+// DBConfig holds everything connectDB needs to build a DSN and tune the pool.
+type DBConfig struct {
+	Username           string `mapstructure:"username"`
+	Password           string `mapstructure:"password"`
+	PasswordFile       string `mapstructure:"password_file"`
+	Host               string `mapstructure:"host"`
+	Port               string `mapstructure:"port"`
+	Name               string `mapstructure:"name"`
+	ParseTime          bool   `mapstructure:"parse_time"`
+	SQLMode            string `mapstructure:"sql_mode"`
+	MaxOpenConns       int    `mapstructure:"max_open_conns"`
+	MaxIdleConns       int    `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime    string `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime    string `mapstructure:"conn_max_idle_time"`
+	ConnectRetryWindow string `mapstructure:"connect_retry_window"`
+}
+
+// Config is the fully-resolved application configuration.
+type Config struct {
+	DB   DBConfig `mapstructure:"db"`
+	Port string   `mapstructure:"port"`
+}
+
+// Overrides carries CLI flag values that, when set, take precedence over
+// everything else. A zero value means "not set by the user".
+type Overrides struct {
+	DBHost string
+	DBPort string
+	DBName string
+	Port   string
+}
+
+// Load resolves Config from defaults, config.{toml,yaml} (searched in ".",
+// "/etc/<appName>", and $XDG_CONFIG_HOME), APP_-prefixed environment
+// variables, and finally overrides, in that order of increasing precedence.
+func Load(appName string, overrides Overrides) (Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	v.SetConfigName("config")
+	v.AddConfigPath(".")
+	v.AddConfigPath(fmt.Sprintf("/etc/%s", appName))
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		v.AddConfigPath(xdg)
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return Config{}, fmt.Errorf("reading config file: %w", err)
+		}
+	}
+
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	if err := bindEnv(v); err != nil {
+		return Config{}, fmt.Errorf("binding environment variables: %w", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	applyOverrides(&cfg, overrides)
+
+	if err := cfg.resolvePasswordFile(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("port", "8000")
+	v.SetDefault("db.port", "3306")
+	v.SetDefault("db.parse_time", true)
+	v.SetDefault("db.max_open_conns", 25)
+	v.SetDefault("db.max_idle_conns", 25)
+	v.SetDefault("db.conn_max_lifetime", "5m")
+	v.SetDefault("db.conn_max_idle_time", "5m")
+	v.SetDefault("db.connect_retry_window", "60s")
+}
+
+// bindEnv binds the DBConfig keys that have no default (and so AutomaticEnv
+// alone won't pick up their env vars, since viper only knows to look for a
+// key's env var once it knows the key exists) to their APP_DB_* variables.
+func bindEnv(v *viper.Viper) error {
+	keys := []string{
+		"db.username",
+		"db.password",
+		"db.password_file",
+		"db.host",
+		"db.name",
+		"db.sql_mode",
+	}
+	for _, key := range keys {
+		if err := v.BindEnv(key); err != nil {
+			return fmt.Errorf("binding %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func applyOverrides(cfg *Config, o Overrides) {
+	if o.DBHost != "" {
+		cfg.DB.Host = o.DBHost
+	}
+	if o.DBPort != "" {
+		cfg.DB.Port = o.DBPort
+	}
+	if o.DBName != "" {
+		cfg.DB.Name = o.DBName
+	}
+	if o.Port != "" {
+		cfg.Port = o.Port
+	}
+}
+
+// resolvePasswordFile reads DB.PasswordFile, if set, into DB.Password so
+// credentials never need to appear directly in the environment (the
+// Docker-secrets pattern).
+func (c *Config) resolvePasswordFile() error {
+	if c.DB.PasswordFile == "" {
+		return nil
+	}
+
+	contents, err := os.ReadFile(c.DB.PasswordFile)
+	if err != nil {
+		return fmt.Errorf("reading db password file: %w", err)
+	}
+
+	c.DB.Password = strings.TrimSpace(string(contents))
+	return nil
+}
+
+// Redacted returns a copy of c with secrets replaced so it's safe to print or
+// log.
+func (c Config) Redacted() Config {
+	redacted := c
+	if redacted.DB.Password != "" {
+		redacted.DB.Password = "********"
+	}
+	return redacted
+}
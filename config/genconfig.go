@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultConfigTemplate is written by GenConfig. Every key is commented out
+// so the file documents the available options without silently overriding
+// env vars or flags for a user who forgets it's there.
+const defaultConfigTemplate = `# Generated by --gen-config. Uncomment and edit as needed.
+# Values here are overridden by APP_-prefixed environment variables, which
+# are in turn overridden by CLI flags.
+
+# port = "8000"
+
+# [db]
+# username = ""
+# password = ""
+# password_file = "/run/secrets/db-password"
+# host = "127.0.0.1"
+# port = "3306"
+# name = "app"
+# parse_time = true
+# sql_mode = ""
+# max_open_conns = 25
+# max_idle_conns = 25
+# conn_max_lifetime = "5m"
+# conn_max_idle_time = "5m"
+# connect_retry_window = "60s"
+`
+
+// GenConfig writes a commented default config file to path, refusing to
+// overwrite an existing file.
+func GenConfig(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists, refusing to overwrite", path)
+	}
+
+	return os.WriteFile(path, []byte(defaultConfigTemplate), 0644)
+}
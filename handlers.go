@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/behradeslamifar/12factor/response"
+	"github.com/behradeslamifar/12factor/users"
+)
+
+const defaultPageLimit = 50
+
+// createUserHandler decodes a User from the request body and delegates
+// validation and persistence to repo.
+func createUserHandler(repo users.Repository) response.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) response.Response {
+		var u users.User
+		if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+			return errorResponse(http.StatusBadRequest, "bad_request", err)
+		}
+
+		created, err := repo.Create(r.Context(), u)
+		if err != nil {
+			return errorFromRepo(err)
+		}
+
+		return response.Created(created)
+	}
+}
+
+// getUsersHandler lists users, honoring ?limit= and ?offset= query params.
+func getUsersHandler(repo users.Repository) response.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) response.Response {
+		limit := defaultPageLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed <= 0 {
+				return errorResponse(http.StatusBadRequest, "bad_request", fmt.Errorf("invalid limit: %q", v))
+			}
+			limit = parsed
+		}
+
+		offset := 0
+		if v := r.URL.Query().Get("offset"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 {
+				return errorResponse(http.StatusBadRequest, "bad_request", fmt.Errorf("invalid offset: %q", v))
+			}
+			offset = parsed
+		}
+
+		list, err := repo.List(r.Context(), limit, offset)
+		if err != nil {
+			return errorFromRepo(err)
+		}
+
+		return response.OK(list)
+	}
+}
+
+// getUserHandler fetches a single user by the {id} path variable.
+func getUserHandler(repo users.Repository) response.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) response.Response {
+		id, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "bad_request", fmt.Errorf("invalid id"))
+		}
+
+		u, err := repo.Get(r.Context(), id)
+		if err != nil {
+			return errorFromRepo(err)
+		}
+
+		return response.OK(u)
+	}
+}
+
+func showFormHandler(w http.ResponseWriter, r *http.Request) {
+	tmpl := template.Must(template.ParseFiles("form.html"))
+	tmpl.Execute(w, nil)
+}
+
+// createHandler is the HTML-form equivalent of createUserHandler, used by the
+// server-rendered page served at "/".
+func createHandler(repo users.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		u := users.User{
+			FirstName: r.FormValue("first_name"),
+			LastName:  r.FormValue("last_name"),
+		}
+
+		if _, err := repo.Create(r.Context(), u); err != nil {
+			errorFromRepo(err).WriteResponse(w)
+			return
+		}
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// errorResponse builds an APIErrorResponse carrying err's message under code.
+func errorResponse(status int, code string, err error) response.Response {
+	return response.APIErrorResponse{Status: status, Code: code, Message: err.Error()}
+}
+
+// errorFromRepo maps a users.Repository error to the appropriate HTTP status
+// so handlers don't each duplicate the same switch.
+func errorFromRepo(err error) response.Response {
+	switch {
+	case errors.Is(err, users.ErrNotFound):
+		return errorResponse(http.StatusNotFound, "not_found", err)
+	case errors.Is(err, users.ErrValidation):
+		return errorResponse(http.StatusBadRequest, "validation", err)
+	case errors.Is(err, users.ErrConflict):
+		return errorResponse(http.StatusConflict, "conflict", err)
+	default:
+		return errorResponse(http.StatusInternalServerError, "internal", errors.New("internal server error"))
+	}
+}
+
+// healthCheckHandler is a liveness check: if the process can answer HTTP
+// requests at all, it reports OK. It deliberately checks no dependencies.
+func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "OK")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/behradeslamifar/12factor/response"
+	"github.com/behradeslamifar/12factor/users"
+)
+
+// mockRepository is an in-memory users.Repository for exercising handlers
+// without a database.
+type mockRepository struct {
+	createFn func(ctx context.Context, u users.User) (users.User, error)
+	listFn   func(ctx context.Context, limit, offset int) ([]users.User, error)
+	getFn    func(ctx context.Context, id int) (users.User, error)
+}
+
+func (m *mockRepository) Create(ctx context.Context, u users.User) (users.User, error) {
+	return m.createFn(ctx, u)
+}
+
+func (m *mockRepository) List(ctx context.Context, limit, offset int) ([]users.User, error) {
+	return m.listFn(ctx, limit, offset)
+}
+
+func (m *mockRepository) Get(ctx context.Context, id int) (users.User, error) {
+	return m.getFn(ctx, id)
+}
+
+func (m *mockRepository) Update(ctx context.Context, u users.User) (users.User, error) {
+	return users.User{}, nil
+}
+
+func (m *mockRepository) Delete(ctx context.Context, id int) error {
+	return nil
+}
+
+func TestCreateUserHandler(t *testing.T) {
+	repo := &mockRepository{
+		createFn: func(ctx context.Context, u users.User) (users.User, error) {
+			u.ID = 1
+			return u, nil
+		},
+	}
+
+	body, _ := json.Marshal(users.User{FirstName: "Ada", LastName: "Lovelace"})
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	response.Wrap(createUserHandler(repo))(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+
+	var got users.User
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.ID != 1 || got.FirstName != "Ada" {
+		t.Fatalf("got %+v, want created user with ID set", got)
+	}
+}
+
+func TestCreateUserHandler_RepoError(t *testing.T) {
+	repo := &mockRepository{
+		createFn: func(ctx context.Context, u users.User) (users.User, error) {
+			return users.User{}, users.ErrConflict
+		},
+	}
+
+	body, _ := json.Marshal(users.User{FirstName: "Ada", LastName: "Lovelace"})
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	response.Wrap(createUserHandler(repo))(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestGetUsersHandler(t *testing.T) {
+	want := []users.User{{ID: 1, FirstName: "Ada", LastName: "Lovelace"}}
+	repo := &mockRepository{
+		listFn: func(ctx context.Context, limit, offset int) ([]users.User, error) {
+			if limit != defaultPageLimit || offset != 0 {
+				t.Fatalf("limit/offset = %d/%d, want defaults", limit, offset)
+			}
+			return want, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+
+	response.Wrap(getUsersHandler(repo))(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var got []users.User
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(got) != 1 || got[0].FirstName != "Ada" {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestGetUsersHandler_InvalidLimit(t *testing.T) {
+	repo := &mockRepository{}
+
+	req := httptest.NewRequest(http.MethodGet, "/users?limit=not-a-number", nil)
+	w := httptest.NewRecorder()
+
+	response.Wrap(getUsersHandler(repo))(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetUserHandler(t *testing.T) {
+	repo := &mockRepository{
+		getFn: func(ctx context.Context, id int) (users.User, error) {
+			if id != 42 {
+				t.Fatalf("id = %d, want 42", id)
+			}
+			return users.User{ID: 42, FirstName: "Ada", LastName: "Lovelace"}, nil
+		},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{id}", response.Wrap(getUserHandler(repo)))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var got users.User
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.ID != 42 {
+		t.Fatalf("got %+v, want ID 42", got)
+	}
+}
+
+func TestGetUserHandler_NotFound(t *testing.T) {
+	repo := &mockRepository{
+		getFn: func(ctx context.Context, id int) (users.User, error) {
+			return users.User{}, users.ErrNotFound
+		},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{id}", response.Wrap(getUserHandler(repo)))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
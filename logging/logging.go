@@ -0,0 +1,66 @@
+// Package logging configures a zerolog.Logger from environment variables so
+// operators can pick format, verbosity, and destination without a code
+// change: LOG_FORMAT=json|console, LOG_LEVEL=debug|info|warn|error,
+// LOG_DESTINATION=stdout|stderr|file:/path|both.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// New builds a zerolog.Logger from the current environment.
+func New() (zerolog.Logger, error) {
+	writer, err := resolveWriter(os.Getenv("LOG_DESTINATION"))
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "console") {
+		writer = zerolog.ConsoleWriter{Out: writer}
+	}
+
+	level, err := resolveLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	logger := zerolog.New(writer).Level(level).With().Timestamp().Logger()
+	return logger, nil
+}
+
+func resolveWriter(destination string) (io.Writer, error) {
+	switch {
+	case destination == "" || destination == "stdout":
+		return os.Stdout, nil
+	case destination == "stderr":
+		return os.Stderr, nil
+	case destination == "both":
+		return zerolog.MultiLevelWriter(os.Stdout, os.Stderr), nil
+	case strings.HasPrefix(destination, "file:"):
+		path := strings.TrimPrefix(destination, "file:")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file %s: %w", path, err)
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("invalid LOG_DESTINATION %q", destination)
+	}
+}
+
+func resolveLevel(level string) (zerolog.Level, error) {
+	if level == "" {
+		return zerolog.InfoLevel, nil
+	}
+
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return zerolog.InfoLevel, fmt.Errorf("invalid LOG_LEVEL %q: %w", level, err)
+	}
+	return parsed, nil
+}
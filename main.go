@@ -1,201 +1,188 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
-	"html/template"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
 	"log"
-	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/behradeslamifar/12factor/config"
+	"github.com/behradeslamifar/12factor/logging"
+	"github.com/behradeslamifar/12factor/metrics"
+	"github.com/behradeslamifar/12factor/migrations"
+	"github.com/behradeslamifar/12factor/users"
 )
 
-type Config struct {
-	DBUsername string
-	DBPassword string
-	DBHost     string
-	DBPort     string
-	DBName     string
-}
-
-type User struct {
-	ID        int    `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-}
+const appName = "12factor"
 
 func main() {
-	loggingDestination := os.Getenv("LOG_DESTINATION")
-	switch loggingDestination {
-	case "file":
-		setupFileLogging()
-	case "stdout":
-		setupStdoutLogging()
-	default:
-		log.Println("Invalid logging destination specified. Defaulting to stdout.")
-		setupStdoutLogging()
+	var (
+		printConfig = flag.Bool("print-config", false, "print the effective configuration (secrets redacted) and exit")
+		genConfig   = flag.String("gen-config", "", "write a commented default config file to the given path and exit")
+		dbHostFlag  = flag.String("db-host", "", "database host (overrides config file and env)")
+		dbPortFlag  = flag.String("db-port", "", "database port (overrides config file and env)")
+		dbNameFlag  = flag.String("db-name", "", "database name (overrides config file and env)")
+		portFlag    = flag.String("port", "", "HTTP port to listen on (overrides config file and env)")
+		migrateOnly = flag.Bool("migrate-only", false, "apply pending migrations and exit")
+		reset       = flag.Bool("reset", false, "wipe the schema and re-apply all migrations, then exit (dev only)")
+	)
+	flag.Parse()
+
+	if *genConfig != "" {
+		if err := config.GenConfig(*genConfig); err != nil {
+			log.Fatalf("Error generating config: %v", err)
+		}
+		fmt.Printf("Wrote default config to %s\n", *genConfig)
+		return
 	}
 
-	log.Println("Starting application")
-
-	config, err := loadConfig()
+	cfg, err := config.Load(appName, config.Overrides{
+		DBHost: *dbHostFlag,
+		DBPort: *dbPortFlag,
+		DBName: *dbNameFlag,
+		Port:   *portFlag,
+	})
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
-	db, err := connectDB(config)
+	if *printConfig {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(cfg.Redacted())
+		return
+	}
+
+	logger, err := logging.New()
 	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
+		log.Fatalf("Error configuring logger: %v", err)
 	}
-	defer db.Close()
 
-	fmt.Println("Connected to MariaDB!")
+	logger.Info().Msg("starting application")
 
-	r := mux.NewRouter()
-	r.HandleFunc("/users", createUserHandler(db)).Methods("POST")
-	r.HandleFunc("/users", getUsersHandler(db)).Methods("GET")
-	r.HandleFunc("/", showFormHandler).Methods("GET")
-	r.HandleFunc("/create", createHandler(db)).Methods("POST")
-	r.HandleFunc("/health", healthCheckHandler).Methods("GET")           // Liveness check
-	r.HandleFunc("/readiness", readinessCheckHandler(db)).Methods("GET") // Readiness check
+	db, err := connectDB(cfg, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("error connecting to database")
+	}
 
-	http.Handle("/", r)
+	logger.Info().Msg("connected to MariaDB")
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
+	if *reset {
+		if err := migrations.Reset(context.Background(), db); err != nil {
+			logger.Fatal().Err(err).Msg("error resetting schema")
+		}
+		logger.Info().Msg("schema reset and migrations applied")
+		return
 	}
 
-	log.Printf("Server listening on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
-}
+	if err := migrations.Run(context.Background(), db); err != nil {
+		logger.Fatal().Err(err).Msg("error applying migrations")
+	}
 
-func loadConfig() (Config, error) {
-	var config Config
+	if *migrateOnly {
+		logger.Info().Msg("migrations applied")
+		return
+	}
 
-	err := godotenv.Load(".env")
-	if err == nil {
-		config.DBUsername = os.Getenv("DB_USERNAME")
-		config.DBPassword = os.Getenv("DB_PASSWORD")
-		config.DBHost = os.Getenv("DB_HOST")
-		config.DBPort = os.Getenv("DB_PORT")
-		config.DBName = os.Getenv("DB_NAME")
+	userRepo, err := users.NewSQLXRepository(db, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("error preparing user repository")
 	}
 
-	return config, nil
+	metrics.RegisterDBStats(db)
+
+	app := NewApp(cfg, db, userRepo, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.Serve(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("server error")
+	}
+
+	logger.Info().Msg("server shut down cleanly")
 }
 
-func connectDB(config Config) (*sql.DB, error) {
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", config.DBUsername, config.DBPassword, config.DBHost, config.DBPort, config.DBName)
-	db, err := sql.Open("mysql", connectionString)
+// connectDB opens the DB pool and retries db.Ping with exponential backoff
+// for up to cfg.DB.ConnectRetryWindow, so the app survives a database
+// container that's still starting up.
+func connectDB(cfg config.Config, logger zerolog.Logger) (*sqlx.DB, error) {
+	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=%t",
+		cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name, cfg.DB.ParseTime)
+	if cfg.DB.SQLMode != "" {
+		connectionString += "&sql_mode=" + cfg.DB.SQLMode
+	}
+
+	db, err := sqlx.Open("mysql", connectionString)
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.Ping()
+	retryWindow, err := time.ParseDuration(cfg.DB.ConnectRetryWindow)
 	if err != nil {
+		return nil, fmt.Errorf("invalid db.connect_retry_window: %w", err)
+	}
+
+	if err := pingWithBackoff(db, retryWindow, logger); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := applyPoolSettings(db, cfg.DB); err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
 
-func createUserHandler(db *sql.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var user User
-		err := json.NewDecoder(r.Body).Decode(&user)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+func pingWithBackoff(db *sqlx.DB, window time.Duration, logger zerolog.Logger) error {
+	deadline := time.Now().Add(window)
+	backoff := 250 * time.Millisecond
+	const maxBackoff = 5 * time.Second
 
-		insertQuery := "INSERT INTO users (first_name, last_name) VALUES (?, ?)"
-		_, err = db.Exec(insertQuery, user.FirstName, user.LastName)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	var lastErr error
+	for {
+		lastErr = db.Ping()
+		if lastErr == nil {
+			return nil
 		}
 
-		w.WriteHeader(http.StatusCreated)
-	}
-}
-
-func getUsersHandler(db *sql.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		rows, err := db.Query("SELECT id, first_name, last_name FROM users")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer rows.Close()
-
-		var users []User
-		for rows.Next() {
-			var user User
-			err := rows.Scan(&user.ID, &user.FirstName, &user.LastName)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			users = append(users, user)
+		if time.Now().After(deadline) {
+			return fmt.Errorf("database not reachable after %s: %w", window, lastErr)
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(users)
+		logger.Warn().Err(lastErr).Dur("retry_in", backoff).Msg("database not ready yet, retrying")
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
 	}
 }
 
-func showFormHandler(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.ParseFiles("form.html"))
-	tmpl.Execute(w, nil)
-}
-
-func createHandler(db *sql.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		r.ParseForm()
-		firstName := r.FormValue("first_name")
-		lastName := r.FormValue("last_name")
-
-		insertQuery := "INSERT INTO users (first_name, last_name) VALUES (?, ?)"
-		_, err := db.Exec(insertQuery, firstName, lastName)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+func applyPoolSettings(db *sqlx.DB, dbCfg config.DBConfig) error {
+	db.SetMaxOpenConns(dbCfg.MaxOpenConns)
+	db.SetMaxIdleConns(dbCfg.MaxIdleConns)
 
-		http.Redirect(w, r, "/", http.StatusSeeOther)
+	connMaxLifetime, err := time.ParseDuration(dbCfg.ConnMaxLifetime)
+	if err != nil {
+		return fmt.Errorf("invalid db.conn_max_lifetime: %w", err)
 	}
-}
+	db.SetConnMaxLifetime(connMaxLifetime)
 
-func setupFileLogging() {
-	logFile, err := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	connMaxIdleTime, err := time.ParseDuration(dbCfg.ConnMaxIdleTime)
 	if err != nil {
-		log.Fatal("Error opening log file:", err)
+		return fmt.Errorf("invalid db.conn_max_idle_time: %w", err)
 	}
-	log.SetOutput(logFile)
-}
-
-func setupStdoutLogging() {
-	log.SetOutput(os.Stdout)
-}
+	db.SetConnMaxIdleTime(connMaxIdleTime)
 
-func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintln(w, "OK")
-}
-
-func readinessCheckHandler(db *sql.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if err := db.Ping(); err != nil {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			fmt.Fprintln(w, "Database is not available")
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintln(w, "OK")
-	}
+	return nil
 }
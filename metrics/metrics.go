@@ -0,0 +1,45 @@
+// Package metrics exposes Prometheus metrics for the HTTP layer, the Go
+// runtime, and the database connection pool.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests handled, by route, method, and status.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by route, method, and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+// ObserveRequest records one completed HTTP request.
+func ObserveRequest(route, method, status string, duration time.Duration) {
+	requestsTotal.WithLabelValues(route, method, status).Inc()
+	requestDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// RegisterDBStats registers open/in-use/idle connection gauges sourced from
+// db.Stats(). Call this once at startup.
+func RegisterDBStats(db *sqlx.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db.DB, "app"))
+}
+
+// Handler serves the accumulated metrics in the Prometheus exposition
+// format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
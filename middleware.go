@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/behradeslamifar/12factor/logging"
+	"github.com/behradeslamifar/12factor/metrics"
+)
+
+// middleware wraps an http.HandlerFunc to add cross-cutting behavior.
+type middleware func(http.HandlerFunc) http.HandlerFunc
+
+// newMiddlewareChain composes request logging and metrics (always applied,
+// in that order, so they cover the full chain) with any additional
+// middleware.
+func newMiddlewareChain(logger zerolog.Logger, mws ...middleware) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		handler := next
+		for i := len(mws) - 1; i >= 0; i-- {
+			handler = mws[i](handler)
+		}
+		return loggingMiddleware(logger)(metricsMiddleware(handler))
+	}
+}
+
+// metricsMiddleware records a Prometheus counter and latency histogram for
+// every request, labeled by route template (not the raw path, to keep
+// cardinality bounded), method, and status.
+func metricsMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		sw, ok := w.(*statusWriter)
+		if !ok {
+			sw = &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		}
+
+		next(sw, r)
+
+		route := r.URL.Path
+		if tmpl, err := mux.CurrentRoute(r).GetPathTemplate(); err == nil {
+			route = tmpl
+		}
+
+		metrics.ObserveRequest(route, r.Method, strconv.Itoa(sw.status), time.Since(start))
+	}
+}
+
+// loggingMiddleware generates a per-request ID, attaches a logger tagged with
+// it to the request context (so downstream code can log with the same
+// request_id), echoes the ID in the X-Request-ID response header, and logs
+// method/path/status/latency once the handler returns.
+func loggingMiddleware(logger zerolog.Logger) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := uuid.NewString()
+			w.Header().Set("X-Request-ID", requestID)
+
+			reqLogger := logger.With().Str("request_id", requestID).Logger()
+			ctx := logging.WithRequestID(r.Context(), requestID)
+			ctx = reqLogger.WithContext(ctx)
+			r = r.WithContext(ctx)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next(sw, r)
+
+			event := reqLogger.Info()
+			if sw.status >= http.StatusInternalServerError {
+				event = reqLogger.Error()
+			}
+			route := r.URL.Path
+			if tmpl, err := mux.CurrentRoute(r).GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+
+			event.
+				Str("method", r.Method).
+				Str("route", route).
+				Int("status", sw.status).
+				Dur("latency", time.Since(start)).
+				Msg("handled request")
+		}
+	}
+}
+
+// recoverMiddleware converts a panic in a downstream handler into a JSON 500
+// response instead of crashing the server.
+func recoverMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logging.FromContext(r.Context()).Error().Interface("panic", rec).Msg("panic recovered")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+			}
+		}()
+		next(w, r)
+	}
+}
+
+// corsMiddleware allows cross-origin requests from any client; tighten this
+// once the app has a known set of frontends to serve.
+func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// maxBodyMiddleware caps request bodies at limit bytes: a handler that reads
+// past the limit gets an error from the body instead of the extra bytes.
+func maxBodyMiddleware(limit int64) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next(w, r)
+		}
+	}
+}
+
+// statusWriter captures the status code written by a handler so middleware
+// can log it after the fact.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,180 @@
+// Package migrations applies the schema changes embedded under sql/ to a
+// MariaDB database, tracking which ones have already run in a
+// schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed sql/*.sql
+var sqlFS embed.FS
+
+// migration is a single numbered SQL file under sql/.
+type migration struct {
+	Version string
+	SQL     string
+}
+
+// load reads every embedded migration, sorted by filename so versions apply
+// in order.
+func load() ([]migration, error) {
+	entries, err := fs.ReadDir(sqlFS, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded migrations: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	migrations := make([]migration, 0, len(names))
+	for _, name := range names {
+		contents, err := sqlFS.ReadFile("sql/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migration{Version: name, SQL: string(contents)})
+	}
+
+	return migrations, nil
+}
+
+// Run applies every migration that hasn't already been recorded in
+// schema_migrations, in order, each inside its own transaction.
+func Run(ctx context.Context, db *sqlx.DB) error {
+	if err := ensureSchemaMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	all, err := load()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range all {
+		if applied[m.Version] {
+			continue
+		}
+
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Pending reports how many embedded migrations have not yet been recorded in
+// schema_migrations, so callers like a readiness check can tell whether the
+// schema is up to date without applying anything.
+func Pending(ctx context.Context, db *sqlx.DB) (int, error) {
+	if err := ensureSchemaMigrationsTable(ctx, db); err != nil {
+		return 0, err
+	}
+
+	all, err := load()
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, m := range all {
+		if !applied[m.Version] {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// Reset drops every table managed by this schema and re-applies every
+// migration from scratch. It is only intended for development environments.
+func Reset(ctx context.Context, db *sqlx.DB) error {
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS users, schema_migrations"); err != nil {
+		return fmt.Errorf("dropping tables: %w", err)
+	}
+
+	return Run(ctx, db)
+}
+
+func ensureSchemaMigrationsTable(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *sqlx.DB) (map[string]bool, error) {
+	var versions []string
+	if err := db.SelectContext(ctx, &versions, "SELECT version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+	return applied, nil
+}
+
+func applyMigration(ctx context.Context, db *sqlx.DB, m migration) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(m.SQL) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES (?)", m.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a migration file on ';' into individual statements,
+// since the connection pool doesn't set multiStatements=true (it's a
+// footgun on a connection shared with application queries). This is a plain
+// split, not a SQL parser, so migration files must not put a ';' inside a
+// string, comment, or routine body.
+func splitStatements(sql string) []string {
+	parts := strings.Split(sql, ";")
+	stmts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			stmts = append(stmts, trimmed)
+		}
+	}
+	return stmts
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/behradeslamifar/12factor/migrations"
+)
+
+// checkResult is the per-dependency detail in a readiness/startup response.
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readinessCheckHandler reports whether the app's dependencies (DB
+// connectivity and schema migrations) are healthy, similar in spirit to a
+// Kubernetes readiness probe. It returns 503 with per-check detail if
+// anything is unhealthy.
+func readinessCheckHandler(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+
+		checks := map[string]checkResult{
+			"db":         checkDB(ctx, db),
+			"migrations": checkMigrations(ctx, db),
+		}
+
+		status := http.StatusOK
+		overall := "ready"
+		for _, c := range checks {
+			if c.Status != "ok" {
+				status = http.StatusServiceUnavailable
+				overall = "not_ready"
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": overall,
+			"checks": checks,
+		})
+	}
+}
+
+// startupCheckHandler reports whether the app has finished booting, similar
+// in spirit to a Kubernetes startup probe. Unlike readinessCheckHandler it
+// checks DB connectivity only, not migrations, so a slow-running migration
+// doesn't make the startup probe fail and the pod get killed mid-migration.
+func startupCheckHandler(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+
+		check := checkDB(ctx, db)
+
+		status := http.StatusOK
+		overall := "started"
+		if check.Status != "ok" {
+			status = http.StatusServiceUnavailable
+			overall = "starting"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": overall,
+			"checks": map[string]checkResult{"db": check},
+		})
+	}
+}
+
+func checkDB(ctx context.Context, db *sqlx.DB) checkResult {
+	if err := db.PingContext(ctx); err != nil {
+		return checkResult{Status: "error", Error: err.Error()}
+	}
+	return checkResult{Status: "ok"}
+}
+
+func checkMigrations(ctx context.Context, db *sqlx.DB) checkResult {
+	pending, err := migrations.Pending(ctx, db)
+	if err != nil {
+		return checkResult{Status: "error", Error: err.Error()}
+	}
+	if pending > 0 {
+		return checkResult{Status: "error", Error: "pending migrations"}
+	}
+	return checkResult{Status: "ok"}
+}
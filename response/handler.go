@@ -0,0 +1,17 @@
+package response
+
+import "net/http"
+
+// HandlerFunc is an HTTP handler that returns its result as a Response
+// instead of writing directly to the ResponseWriter.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) Response
+
+// Wrap adapts a HandlerFunc into a standard http.HandlerFunc, writing
+// whatever Response the handler returns.
+func Wrap(fn HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if resp := fn(w, r); resp != nil {
+			resp.WriteResponse(w)
+		}
+	}
+}
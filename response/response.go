@@ -0,0 +1,59 @@
+// Package response provides a uniform JSON envelope for HTTP handlers, so
+// callers get consistent success and error shapes regardless of which
+// handler produced them.
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Response knows how to write itself to an http.ResponseWriter, including
+// status code and headers.
+type Response interface {
+	WriteResponse(w http.ResponseWriter)
+}
+
+// APIResponse wraps a successful result. Data is marshaled as-is under the
+// response body.
+type APIResponse struct {
+	Status int
+	Data   any
+}
+
+// OK builds a 200 APIResponse for data.
+func OK(data any) APIResponse {
+	return APIResponse{Status: http.StatusOK, Data: data}
+}
+
+// Created builds a 201 APIResponse for data.
+func Created(data any) APIResponse {
+	return APIResponse{Status: http.StatusCreated, Data: data}
+}
+
+func (r APIResponse) WriteResponse(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Status)
+	json.NewEncoder(w).Encode(r.Data)
+}
+
+// APIErrorResponse wraps a failed result. It marshals as
+// {"error":{"code":"...","message":"...","details":...}}.
+type APIErrorResponse struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+}
+
+func (r APIErrorResponse) WriteResponse(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    r.Code,
+			"message": r.Message,
+			"details": r.Details,
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package users
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get when no user exists with the given ID.
+var ErrNotFound = errors.New("user not found")
+
+// ErrConflict is returned by Create when the write violates a uniqueness
+// constraint on the users table.
+var ErrConflict = errors.New("user already exists")
+
+// Repository abstracts persistence for User records so handlers can depend on
+// an interface instead of a concrete database driver.
+type Repository interface {
+	Create(ctx context.Context, u User) (User, error)
+	List(ctx context.Context, limit, offset int) ([]User, error)
+	Get(ctx context.Context, id int) (User, error)
+	Update(ctx context.Context, u User) (User, error)
+	Delete(ctx context.Context, id int) error
+}
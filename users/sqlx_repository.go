@@ -0,0 +1,169 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+
+	"github.com/behradeslamifar/12factor/logging"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for a unique-constraint
+// violation (ER_DUP_ENTRY).
+const mysqlDuplicateEntry = 1062
+
+// sqlxRepository is a Repository backed by jmoiron/sqlx with prepared
+// statements for every query.
+type sqlxRepository struct {
+	db     *sqlx.DB
+	logger zerolog.Logger
+
+	insertStmt *sqlx.Stmt
+	selectStmt *sqlx.Stmt
+	listStmt   *sqlx.Stmt
+	updateStmt *sqlx.Stmt
+	deleteStmt *sqlx.Stmt
+}
+
+// NewSQLXRepository prepares the statements used by Repository against db
+// and returns a Repository backed by them. The returned repository owns the
+// prepared statements but not db itself. Query failures are logged via
+// logger with the query name and duration, never the raw SQL.
+func NewSQLXRepository(db *sqlx.DB, logger zerolog.Logger) (Repository, error) {
+	repo := &sqlxRepository{db: db, logger: logger}
+
+	var err error
+	if repo.insertStmt, err = db.Preparex(`INSERT INTO users (first_name, last_name) VALUES (?, ?)`); err != nil {
+		return nil, err
+	}
+	if repo.selectStmt, err = db.Preparex(`SELECT id, first_name, last_name FROM users WHERE id = ?`); err != nil {
+		return nil, err
+	}
+	if repo.listStmt, err = db.Preparex(`SELECT id, first_name, last_name FROM users ORDER BY id LIMIT ? OFFSET ?`); err != nil {
+		return nil, err
+	}
+	if repo.updateStmt, err = db.Preparex(`UPDATE users SET first_name = ?, last_name = ? WHERE id = ?`); err != nil {
+		return nil, err
+	}
+	if repo.deleteStmt, err = db.Preparex(`DELETE FROM users WHERE id = ?`); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// logQueryError logs a failed query by name and duration, tagged with the
+// request ID from ctx if one is present.
+func (r *sqlxRepository) logQueryError(ctx context.Context, query string, start time.Time, err error) {
+	r.logger.Error().
+		Str("query", query).
+		Str("request_id", logging.RequestID(ctx)).
+		Dur("duration", time.Since(start)).
+		Err(err).
+		Msg("query failed")
+}
+
+func (r *sqlxRepository) Create(ctx context.Context, u User) (User, error) {
+	if err := u.Validate(); err != nil {
+		return User{}, err
+	}
+
+	start := time.Now()
+	result, err := r.insertStmt.ExecContext(ctx, u.FirstName, u.LastName)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return User{}, ErrConflict
+		}
+		r.logQueryError(ctx, "create_user", start, err)
+		return User{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logQueryError(ctx, "create_user", start, err)
+		return User{}, err
+	}
+	u.ID = int(id)
+
+	return u, nil
+}
+
+func (r *sqlxRepository) List(ctx context.Context, limit, offset int) ([]User, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	start := time.Now()
+	var list []User
+	if err := r.listStmt.SelectContext(ctx, &list, limit, offset); err != nil {
+		r.logQueryError(ctx, "list_users", start, err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *sqlxRepository) Get(ctx context.Context, id int) (User, error) {
+	start := time.Now()
+	var u User
+	err := r.selectStmt.GetContext(ctx, &u, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		r.logQueryError(ctx, "get_user", start, err)
+		return User{}, err
+	}
+
+	return u, nil
+}
+
+func (r *sqlxRepository) Update(ctx context.Context, u User) (User, error) {
+	if err := u.Validate(); err != nil {
+		return User{}, err
+	}
+
+	start := time.Now()
+	result, err := r.updateStmt.ExecContext(ctx, u.FirstName, u.LastName, u.ID)
+	if err != nil {
+		r.logQueryError(ctx, "update_user", start, err)
+		return User{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logQueryError(ctx, "update_user", start, err)
+		return User{}, err
+	}
+	if affected == 0 {
+		return User{}, ErrNotFound
+	}
+
+	return u, nil
+}
+
+func (r *sqlxRepository) Delete(ctx context.Context, id int) error {
+	start := time.Now()
+	result, err := r.deleteStmt.ExecContext(ctx, id)
+	if err != nil {
+		r.logQueryError(ctx, "delete_user", start, err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logQueryError(ctx, "delete_user", start, err)
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package users provides the data-access layer for user records, isolating
+// SQL from the HTTP handlers that consume it.
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxNameLength bounds FirstName/LastName so a handler can't write
+// unreasonably large values into the users table.
+const maxNameLength = 255
+
+// User is a single row in the users table.
+type User struct {
+	ID        int    `json:"id" db:"id"`
+	FirstName string `json:"first_name" db:"first_name"`
+	LastName  string `json:"last_name" db:"last_name"`
+}
+
+// ErrValidation indicates the caller-supplied User failed validation.
+var ErrValidation = errors.New("validation failed")
+
+// Validate checks that FirstName and LastName are present and within the
+// length limits enforced by the users table.
+func (u User) Validate() error {
+	first := strings.TrimSpace(u.FirstName)
+	last := strings.TrimSpace(u.LastName)
+
+	switch {
+	case first == "":
+		return fmt.Errorf("%w: first_name is required", ErrValidation)
+	case last == "":
+		return fmt.Errorf("%w: last_name is required", ErrValidation)
+	case utf8.RuneCountInString(first) > maxNameLength:
+		return fmt.Errorf("%w: first_name exceeds %d characters", ErrValidation, maxNameLength)
+	case utf8.RuneCountInString(last) > maxNameLength:
+		return fmt.Errorf("%w: last_name exceeds %d characters", ErrValidation, maxNameLength)
+	}
+
+	return nil
+}
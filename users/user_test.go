@@ -0,0 +1,59 @@
+package users
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUserValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    User
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			user: User{FirstName: "Ada", LastName: "Lovelace"},
+		},
+		{
+			name:    "missing first name",
+			user:    User{FirstName: "  ", LastName: "Lovelace"},
+			wantErr: true,
+		},
+		{
+			name:    "missing last name",
+			user:    User{FirstName: "Ada", LastName: ""},
+			wantErr: true,
+		},
+		{
+			name:    "first name too long",
+			user:    User{FirstName: strings.Repeat("a", maxNameLength+1), LastName: "Lovelace"},
+			wantErr: true,
+		},
+		{
+			name:    "last name too long",
+			user:    User{FirstName: "Ada", LastName: strings.Repeat("a", maxNameLength+1)},
+			wantErr: true,
+		},
+		{
+			name: "name at max length",
+			user: User{FirstName: strings.Repeat("a", maxNameLength), LastName: strings.Repeat("a", maxNameLength)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.user.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Fatalf("Validate() = %v, want error wrapping ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}